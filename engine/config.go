@@ -9,6 +9,7 @@ import (
 )
 
 func init() {
+	DeclFunc("RegionConfig", RegionConfig, "Composes configurations per region, as defined by the regions map")
 	DeclFunc("Uniform", Uniform, "Uniform magnetization in given direction")
 	DeclFunc("Vortex", Vortex, "Vortex magnetization with given circulation and core polarization")
 	DeclFunc("Antivortex", AntiVortex, "Antivortex magnetization with given circulation and core polarization")
@@ -19,6 +20,9 @@ func init() {
 	DeclFunc("RandomMag", RandomMag, "Random magnetization")
 	DeclFunc("RandomMagSeed", RandomMagSeed, "Random magnetization with given seed")
 	DeclFunc("Helical", HelicalMag, "Helical magnetization with helical length Ld and with q-vector along (qx, qy)")
+	DeclFunc("SkyrmionLattice", SkyrmionLattice, "Lattice of skyrmions with given charge, core polarization, lattice spacing and kind (\"neel\" or \"bloch\")")
+	DeclFunc("Hopfion", Hopfion, "Hopfion magnetization with given Hopf index Q and core radius R")
+	DeclFunc("BlochPoint", BlochPoint, "3D radial hedgehog (Bloch point) magnetization with given core polarization")
 }
 
 // Magnetic configuration returns m vector for position (x,y,z)
@@ -96,6 +100,68 @@ func BlochSkyrmion(charge, pol int) Config {
 	}
 }
 
+// SkyrmionLattice places skyrmion cores of the given kind ("neel" or
+// "bloch") with given charge and core polarization on a hexagonal lattice
+// of spacing a, i.e. alternating rows offset by a/2 in x and spaced by
+// a*sqrt(3)/2 in y. Rather than hard-partitioning space by nearest site
+// (which would leave a discontinuity at every cell boundary), each point
+// blends the contributions of its surrounding lattice sites, weighted by
+// the same Gaussian core envelope used in NeelSkyrmion/BlochSkyrmion: that
+// weight is already ~1 at a site's own core and decays smoothly to ~0 well
+// before the next site, so the blend reduces to the single nearest core
+// almost everywhere and only mixes tails where they actually overlap. E.g.:
+// 	m = SkyrmionLattice(1, 1, 50e-9, "neel") // hexagonal Néel skyrmion crystal
+func SkyrmionLattice(charge, pol int, a float64, kind string) Config {
+	w := 8 * Mesh().CellSize()[X]
+	w2 := w * w
+	ay := a * math.Sqrt(3) / 2
+
+	// core returns the unblended texture at offset (dx,dy) from a lattice
+	// site, plus the Gaussian weight used both to shape mz and to blend
+	// this site against its neighbours.
+	core := func(dx, dy float64) (data.Vector, float64) {
+		r2 := dx*dx + dy*dy
+		weight := math.Exp(-r2 / w2)
+		mz := 2 * float64(pol) * (weight - 0.5)
+		var mx, my float64
+		if r2 > 0 {
+			r := math.Sqrt(r2)
+			if kind == "bloch" {
+				mx = (-dy * float64(charge) / r) * (1 - math.Abs(mz))
+				my = (dx * float64(charge) / r) * (1 - math.Abs(mz))
+			} else {
+				mx = (dx * float64(charge) / r) * (1 - math.Abs(mz))
+				my = (dy * float64(charge) / r) * (1 - math.Abs(mz))
+			}
+		}
+		return data.Vector{mx, my, mz}, weight
+	}
+
+	return func(x, y, z float64) data.Vector {
+		row0 := math.Round(y / ay)
+		var sum data.Vector
+		var wSum float64
+		for dr := -1; dr <= 1; dr++ {
+			row := row0 + float64(dr)
+			xOff := 0.0
+			if math.Mod(math.Abs(row), 2) != 0 {
+				xOff = a / 2
+			}
+			col0 := math.Round((x - xOff) / a)
+			for dc := -1; dc <= 1; dc++ {
+				col := col0 + float64(dc)
+				v, wt := core(x-xOff-col*a, y-row*ay)
+				sum = sum.MAdd(wt, v)
+				wSum += wt
+			}
+		}
+		if wSum == 0 {
+			return data.Vector{0, 0, -float64(pol)}
+		}
+		return noNaN(data.Vector{sum[X] / wSum, sum[Y] / wSum, sum[Z] / wSum}, pol)
+	}
+}
+
 func AntiVortex(circ, pol int) Config {
 	diam2 := 2 * sqr64(Mesh().CellSize()[X])
 	return func(x, y, z float64) data.Vector {
@@ -123,6 +189,75 @@ func VortexWall(mleft, mright float64, circ, pol int) Config {
 	}
 }
 
+// Hopfion returns a magnetization texture with Hopf index Q and core
+// radius R, built from the standard stereographic (u, v) parametrization
+// of the Hopf map S^3 -> S^2: with ρ = sqrt(x²+y²)/R, ζ = z/R and
+// d = 1+ρ²+ζ², u = (4ρ/d)e^{iφ} and v = (2ζ + i(ρ²+ζ²-1))/d, the raw
+// texture is w = u v̄^Q, (2Re(w), 2Im(w), |u|²-|v|^(2Q)). Dividing that by
+// |u|²+|v|^(2Q) is what makes (2Re(w))²+(2Im(w))²+mz² collapse to 1 for
+// every Q, not just Q=1, so the result is a genuine unit vector. E.g.:
+// 	m = Hopfion(1, 30e-9) // Q=1 hopfion of radius 30nm
+func Hopfion(Q int, R float64) Config {
+	return func(x, y, z float64) data.Vector {
+		rho := math.Sqrt(x*x+y*y) / R
+		zeta := z / R
+		d := 1 + rho*rho + zeta*zeta
+		phi := math.Atan2(y, x)
+
+		uAbs := 4 * rho / d
+		uRe := uAbs * math.Cos(phi)
+		uIm := uAbs * math.Sin(phi)
+
+		vRe := 2 * zeta / d
+		vIm := (rho*rho + zeta*zeta - 1) / d
+		vAbs := math.Hypot(vRe, vIm)
+
+		// v̄^Q in polar form
+		vConjAbs := math.Pow(vAbs, float64(Q))
+		vConjArg := -float64(Q) * math.Atan2(vIm, vRe)
+		vConjRe := vConjAbs * math.Cos(vConjArg)
+		vConjIm := vConjAbs * math.Sin(vConjArg)
+
+		// u * v̄^Q
+		wRe := uRe*vConjRe - uIm*vConjIm
+		wIm := uRe*vConjIm + uIm*vConjRe
+
+		uAbs2 := uAbs * uAbs
+		vAbs2Q := vConjAbs * vConjAbs // = |v|^(2Q)
+		denom := uAbs2 + vAbs2Q
+
+		m := data.Vector{2 * wRe / denom, 2 * wIm / denom, (uAbs2 - vAbs2Q) / denom}
+		if math.IsNaN(m[X]) || math.IsNaN(m[Y]) || math.IsNaN(m[Z]) {
+			return data.Vector{0, 0, -1}
+		}
+		return m
+	}
+}
+
+// BlochPoint returns a radial hedgehog texture m = r̂ (pol > 0) or m = -r̂
+// (pol < 0). Near the origin r̂ itself is undefined, so instead of letting
+// m collapse to the zero vector there, the polar angle of r̂ is smoothly
+// blended towards the fixed +z axis over a core of radius
+// Mesh().CellSize()[X]: this keeps m a well-defined unit vector everywhere,
+// equal to ±ẑ exactly at the origin and relaxing to ±r̂ outside the core.
+func BlochPoint(pol int) Config {
+	core := Mesh().CellSize()[X]
+	core2 := core * core
+	p := float64(pol)
+	return func(x, y, z float64) data.Vector {
+		rho := math.Sqrt(x*x + y*y)
+		r2 := rho*rho + z*z
+		phi := math.Atan2(y, x)
+		thetaDir := math.Atan2(rho, z) // polar angle of r̂, 0 along +z
+
+		w := math.Exp(-r2 / core2) // 1 at the origin, ->0 outside the core
+		theta := (1 - w) * thetaDir
+
+		sinT, cosT := math.Sincos(theta)
+		return data.Vector{p * sinT * math.Cos(phi), p * sinT * math.Sin(phi), p * cosT}
+	}
+}
+
 func noNaN(v data.Vector, pol int) data.Vector {
 	if math.IsNaN(v[X]) || math.IsNaN(v[Y]) || math.IsNaN(v[Z]) {
 		return data.Vector{0, 0, float64(pol)}
@@ -206,6 +341,24 @@ func (c Config) Scale(sx, sy, sz float64) Config {
 	}
 }
 
+// Tile returns a copy of c periodically repeated with periods ax, ay, az
+// along x, y, z. A period of 0 leaves that axis untiled. E.g.:
+// 	M = NeelSkyrmion(1, 1).Tile(50e-9, 50e-9, 0) // square skyrmion lattice
+func (c Config) Tile(ax, ay, az float64) Config {
+	return func(x, y, z float64) data.Vector {
+		if ax != 0 {
+			x = math.Remainder(x, ax)
+		}
+		if ay != 0 {
+			y = math.Remainder(y, ay)
+		}
+		if az != 0 {
+			z = math.Remainder(z, az)
+		}
+		return c(x, y, z)
+	}
+}
+
 // Rotates the configuration around the Z-axis, over θ radians.
 func (c Config) RotZ(θ float64) Config {
 	cos := math.Cos(θ)
@@ -229,3 +382,32 @@ func (c Config) Add(weight float64, other Config) Config {
 		return c(x, y, z).MAdd(weight, other(x, y, z))
 	}
 }
+
+// RegionConfig composes a configuration that evaluates a different Config
+// per mesh region, analogous to assigning per-region material parameters
+// with RegionAdd. E.g.:
+// 	m = RegionConfig(map[int]Config{1: Vortex(1, 1), 2: Uniform(1, 0, 0)})
+// sets a vortex in region 1 and a uniform state in region 2. Regions not
+// present in the map are left at the zero vector; list every region
+// explicitly (or combine with InRegion) if that is not what's wanted.
+func RegionConfig(confByRegion map[int]Config) Config {
+	return func(x, y, z float64) data.Vector {
+		regionID := regions.get(x, y, z)
+		if c, ok := confByRegion[int(regionID)]; ok {
+			return c(x, y, z)
+		}
+		return data.Vector{0, 0, 0}
+	}
+}
+
+// InRegion restricts c to regionID: it evaluates c(x,y,z) where the mesh
+// region at (x,y,z) equals regionID, and the zero vector elsewhere. E.g.:
+// 	m = Vortex(1, 1).InRegion(1)
+func (c Config) InRegion(regionID int) Config {
+	return func(x, y, z float64) data.Vector {
+		if int(regions.get(x, y, z)) == regionID {
+			return c(x, y, z)
+		}
+		return data.Vector{0, 0, 0}
+	}
+}
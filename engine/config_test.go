@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mumax/3/data"
+)
+
+func init() {
+	SetMesh(4, 4, 4, 1e-9, 1e-9, 1e-9, 0, 0, 0)
+}
+
+func TestConfigTilePeriodic(t *testing.T) {
+	base := Vortex(1, 1)
+	tiled := base.Tile(100e-9, 0, 0)
+	a := tiled(10e-9, 5e-9, 0)
+	b := tiled(110e-9, 5e-9, 0) // one period over in x
+	// math.Remainder(110e-9, 100e-9) isn't bit-identical to the literal
+	// 10e-9, so an exact comparison fails on ULP-level noise amplified by
+	// Vortex's sqrt/division; compare with a tolerance like the other
+	// numeric assertions in this file.
+	if math.Abs(a[X]-b[X]) > 1e-9 || math.Abs(a[Y]-b[Y]) > 1e-9 || math.Abs(a[Z]-b[Z]) > 1e-9 {
+		t.Errorf("Tile is not periodic: m(x)=%v, m(x+period)=%v", a, b)
+	}
+}
+
+// With no regions painted, every point reads back as the default region
+// (0), so RegionConfig's entry for region 0 should apply everywhere.
+func TestRegionConfigDefaultRegion(t *testing.T) {
+	c := RegionConfig(map[int]Config{0: Uniform(1, 0, 0)})
+	if m := c(0, 0, 0); m != (data.Vector{1, 0, 0}) {
+		t.Errorf("RegionConfig region 0: got %v, want {1 0 0}", m)
+	}
+}
+
+func TestRegionConfigUnmappedRegionIsZero(t *testing.T) {
+	c := RegionConfig(map[int]Config{5: Uniform(1, 0, 0)})
+	if m := c(0, 0, 0); m != (data.Vector{0, 0, 0}) {
+		t.Errorf("RegionConfig with no entry for the default region: got %v, want zero vector", m)
+	}
+}
+
+func TestInRegionMatchesDefaultRegion(t *testing.T) {
+	c := Uniform(1, 0, 0).InRegion(0)
+	if m := c(0, 0, 0); m != (data.Vector{1, 0, 0}) {
+		t.Errorf("InRegion(0) at default region: got %v, want {1 0 0}", m)
+	}
+}
+
+func TestInRegionIsZeroOutsideRegion(t *testing.T) {
+	c := Uniform(1, 0, 0).InRegion(5)
+	if m := c(0, 0, 0); m != (data.Vector{0, 0, 0}) {
+		t.Errorf("InRegion(5) outside region 5: got %v, want zero vector", m)
+	}
+}
+
+// SkyrmionLattice should blend neighbouring cores instead of hard-switching
+// at the Voronoi boundary between two sites: crossing that boundary by a
+// fraction of a nanometre must change the in-plane components only slightly.
+func TestSkyrmionLatticeIsContinuousAcrossBoundary(t *testing.T) {
+	m := SkyrmionLattice(1, 1, 50e-9, "neel")
+	left := m(24.999e-9, 0, 0)
+	right := m(25.001e-9, 0, 0)
+	dx := left[X] - right[X]
+	dy := left[Y] - right[Y]
+	if math.Abs(dx) > 0.05 || math.Abs(dy) > 0.05 {
+		t.Errorf("discontinuity at lattice boundary: m(left)=%v, m(right)=%v", left, right)
+	}
+}
+
+// Hopfion must return a unit vector for any Hopf index Q, not just Q=1.
+func TestHopfionIsUnitVector(t *testing.T) {
+	pts := [][3]float64{{10e-9, 0, 5e-9}, {0, 0, 0}, {15e-9, 15e-9, -10e-9}}
+	for _, Q := range []int{1, 2, 3} {
+		h := Hopfion(Q, 30e-9)
+		for _, p := range pts {
+			m := h(p[0], p[1], p[2])
+			n := math.Sqrt(m[X]*m[X] + m[Y]*m[Y] + m[Z]*m[Z])
+			if math.Abs(n-1) > 1e-9 {
+				t.Errorf("Hopfion(Q=%d) at %v: |m|=%v, want 1", Q, p, n)
+			}
+		}
+	}
+}
+
+// BlochPoint must not collapse to the zero vector at its own singularity.
+func TestBlochPointOriginIsUnitVector(t *testing.T) {
+	m := BlochPoint(1)(0, 0, 0)
+	n := math.Sqrt(m[X]*m[X] + m[Y]*m[Y] + m[Z]*m[Z])
+	if math.Abs(n-1) > 1e-9 {
+		t.Errorf("BlochPoint at origin: |m|=%v, want 1 (got %v)", n, m)
+	}
+}
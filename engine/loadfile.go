@@ -0,0 +1,246 @@
+package engine
+
+// Load an initial magnetization from a pre-existing OVF1/OVF2 file,
+// possibly living on a remote httpfs node.
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mumax/3/data"
+	"github.com/mumax/3/httpfs"
+)
+
+func init() {
+	DeclFunc("LoadFile", LoadFile, "Loads the magnetization from an OVF file, trilinearly interpolated onto the mesh")
+}
+
+// LoadFile reads the vector field stored in the OVF1/OVF2 file at URL
+// (opened through httpfs.Open, so URL may point at a remote httpfs node)
+// and returns a Config that trilinearly interpolates it at any (x,y,z).
+// Points outside the source file's bounding box use the nearest boundary
+// value. The decoded field is cached, so repeated evaluations during
+// m.Set() don't re-download or re-parse the file. E.g.:
+//
+//	m = LoadFile("http://node2:35367/run.out/m000042.ovf")
+func LoadFile(URL string) Config {
+	f := cachedOVF(URL)
+	return f.interpolate
+}
+
+var ovfCache = make(map[string]*ovfField)
+
+// cachedOVF returns the decoded field for URL, reading and parsing it
+// only the first time it's requested.
+func cachedOVF(URL string) *ovfField {
+	if f, ok := ovfCache[URL]; ok {
+		return f
+	}
+	f := readOVF(URL)
+	ovfCache[URL] = f
+	return f
+}
+
+// ovfField holds a decoded OVF vector field plus the geometry needed to
+// map world coordinates onto it.
+type ovfField struct {
+	nx, ny, nz int
+	ncomp      int
+	cellSize   [3]float64 // xstepsize, ystepsize, zstepsize
+	origin     [3]float64 // xmin, ymin, zmin (of the source bounding box)
+	val        []float32  // flat data, index (( iz*ny + iy)*nx + ix)*ncomp + c
+}
+
+func readOVF(URL string) *ovfField {
+	in := httpfs.MustOpen(URL)
+	defer in.Close()
+	return parseOVF(bufio.NewReader(in))
+}
+
+// parseOVF reads OVF1/OVF2 header key/value lines until it hits the
+// "Begin: Data ..." line, then decodes the data block in text, Binary 4
+// or Binary 8 format.
+func parseOVF(r *bufio.Reader) *ovfField {
+	f := &ovfField{ncomp: 3}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			panic(fmt.Errorf("ovf: unexpected end of header: %v", err))
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		low := strings.ToLower(line)
+
+		switch {
+		case strings.HasPrefix(low, "xnodes:"):
+			f.nx = atoiVal(line)
+		case strings.HasPrefix(low, "ynodes:"):
+			f.ny = atoiVal(line)
+		case strings.HasPrefix(low, "znodes:"):
+			f.nz = atoiVal(line)
+		case strings.HasPrefix(low, "xstepsize:"):
+			f.cellSize[X] = atofVal(line)
+		case strings.HasPrefix(low, "ystepsize:"):
+			f.cellSize[Y] = atofVal(line)
+		case strings.HasPrefix(low, "zstepsize:"):
+			f.cellSize[Z] = atofVal(line)
+		case strings.HasPrefix(low, "xmin:"):
+			f.origin[X] = atofVal(line)
+		case strings.HasPrefix(low, "ymin:"):
+			f.origin[Y] = atofVal(line)
+		case strings.HasPrefix(low, "zmin:"):
+			f.origin[Z] = atofVal(line)
+		case strings.HasPrefix(low, "valuedim:"):
+			f.ncomp = atoiVal(line)
+		case strings.HasPrefix(low, "begin:") && strings.Contains(low, "data"):
+			decodeOVFData(r, f, strings.TrimSpace(line[strings.Index(low, "data")+len("data"):]))
+			return f
+		}
+	}
+}
+
+func atoiVal(line string) int {
+	v, err := strconv.Atoi(strings.TrimSpace(line[strings.Index(line, ":")+1:]))
+	if err != nil {
+		panic(fmt.Errorf("ovf: malformed header line %q: %v", line, err))
+	}
+	return v
+}
+
+func atofVal(line string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(line[strings.Index(line, ":")+1:]), 64)
+	if err != nil {
+		panic(fmt.Errorf("ovf: malformed header line %q: %v", line, err))
+	}
+	return v
+}
+
+// OVF control numbers: every "binary 4"/"binary 8" data block is preceded
+// by this exact sentinel value, which a reader checks to detect a
+// byte-order mismatch or truncated/corrupted file before trusting the
+// data that follows.
+const (
+	ovfControl4 float32 = 1234567.0
+	ovfControl8 float64 = 123456789012345.0
+)
+
+func decodeOVFData(r *bufio.Reader, f *ovfField, format string) {
+	n := f.nx * f.ny * f.nz * f.ncomp
+	f.val = make([]float32, n)
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "text":
+		for i := 0; i < n; {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				panic(fmt.Errorf("ovf: unexpected end of data: %v", err))
+			}
+			for _, tok := range strings.Fields(line) {
+				v, err := strconv.ParseFloat(tok, 32)
+				if err != nil {
+					panic(fmt.Errorf("ovf: malformed data value %q: %v", tok, err))
+				}
+				f.val[i] = float32(v)
+				i++
+			}
+		}
+	case "binary 4":
+		// OVF2 stores binary data little-endian, OVF1 big-endian.
+		var order binary.ByteOrder = binary.LittleEndian
+		if b, err := r.Peek(4); err == nil && math.Float32frombits(binary.BigEndian.Uint32(b)) == ovfControl4 {
+			order = binary.BigEndian
+		}
+		var control float32
+		if err := binary.Read(r, order, &control); err != nil {
+			panic(err)
+		}
+		if control != ovfControl4 {
+			panic(fmt.Errorf("ovf: bad binary 4 control number %v, want %v (wrong byte order or corrupted file)", control, ovfControl4))
+		}
+		if err := binary.Read(r, order, f.val); err != nil {
+			panic(err)
+		}
+	case "binary 8":
+		var order binary.ByteOrder = binary.LittleEndian
+		if b, err := r.Peek(8); err == nil && math.Float64frombits(binary.BigEndian.Uint64(b)) == ovfControl8 {
+			order = binary.BigEndian
+		}
+		var control float64
+		if err := binary.Read(r, order, &control); err != nil {
+			panic(err)
+		}
+		if control != ovfControl8 {
+			panic(fmt.Errorf("ovf: bad binary 8 control number %v, want %v (wrong byte order or corrupted file)", control, ovfControl8))
+		}
+		raw := make([]float64, n)
+		if err := binary.Read(r, order, raw); err != nil {
+			panic(err)
+		}
+		for i, v := range raw {
+			f.val[i] = float32(v)
+		}
+	default:
+		panic(fmt.Errorf("ovf: unsupported data format %q", format))
+	}
+}
+
+// at returns component c at cell (ix,iy,iz), clamping out-of-range
+// indices to the nearest boundary cell (nearest-neighbour extrapolation).
+func (f *ovfField) at(ix, iy, iz, c int) float64 {
+	ix = clampInt(ix, 0, f.nx-1)
+	iy = clampInt(iy, 0, f.ny-1)
+	iz = clampInt(iz, 0, f.nz-1)
+	return float64(f.val[((iz*f.ny+iy)*f.nx+ix)*f.ncomp+c])
+}
+
+func clampInt(i, lo, hi int) int {
+	if i < lo {
+		return lo
+	}
+	if i > hi {
+		return hi
+	}
+	return i
+}
+
+// interpolate trilinearly interpolates the field at world position
+// (x,y,z), extrapolating with the nearest boundary value outside the
+// source file's bounding box.
+func (f *ovfField) interpolate(x, y, z float64) data.Vector {
+	fx := (x-f.origin[X])/f.cellSize[X] - 0.5
+	fy := (y-f.origin[Y])/f.cellSize[Y] - 0.5
+	fz := (z-f.origin[Z])/f.cellSize[Z] - 0.5
+
+	ix0 := math.Floor(fx)
+	iy0 := math.Floor(fy)
+	iz0 := math.Floor(fz)
+	tx := fx - ix0
+	ty := fy - iy0
+	tz := fz - iz0
+
+	var v data.Vector
+	for c := 0; c < 3 && c < f.ncomp; c++ {
+		c000 := f.at(int(ix0), int(iy0), int(iz0), c)
+		c100 := f.at(int(ix0)+1, int(iy0), int(iz0), c)
+		c010 := f.at(int(ix0), int(iy0)+1, int(iz0), c)
+		c110 := f.at(int(ix0)+1, int(iy0)+1, int(iz0), c)
+		c001 := f.at(int(ix0), int(iy0), int(iz0)+1, c)
+		c101 := f.at(int(ix0)+1, int(iy0), int(iz0)+1, c)
+		c011 := f.at(int(ix0), int(iy0)+1, int(iz0)+1, c)
+		c111 := f.at(int(ix0)+1, int(iy0)+1, int(iz0)+1, c)
+
+		c00 := c000*(1-tx) + c100*tx
+		c10 := c010*(1-tx) + c110*tx
+		c01 := c001*(1-tx) + c101*tx
+		c11 := c011*(1-tx) + c111*tx
+
+		c0 := c00*(1-ty) + c10*ty
+		c1 := c01*(1-ty) + c11*ty
+
+		v[c] = c0*(1-tz) + c1*tz
+	}
+	return v
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+const testOVF = `# OVF 2.0
+# xnodes: 2
+# ynodes: 2
+# znodes: 1
+# xstepsize: 1
+# ystepsize: 1
+# zstepsize: 1
+# xmin: 0
+# ymin: 0
+# zmin: 0
+# valuedim: 3
+# Begin: Data text
+0 0 0
+1 0 0
+0 1 0
+1 1 1
+# End: Data text
+`
+
+func TestParseOVF(t *testing.T) {
+	f := parseOVF(bufio.NewReader(strings.NewReader(testOVF)))
+	if f.nx != 2 || f.ny != 2 || f.nz != 1 {
+		t.Fatalf("got grid %dx%dx%d, want 2x2x1", f.nx, f.ny, f.nz)
+	}
+	if f.at(0, 0, 0, 0) != 0 || f.at(1, 0, 0, 0) != 1 || f.at(0, 1, 0, 1) != 1 {
+		t.Fatalf("decoded data does not match input")
+	}
+}
+
+func TestOVFInterpolateExact(t *testing.T) {
+	f := parseOVF(bufio.NewReader(strings.NewReader(testOVF)))
+	// cell centers sit at x,y = 0.5, 1.5, so evaluating exactly there
+	// should reproduce the stored value without any blending.
+	v := f.interpolate(0.5, 0.5, 0.5)
+	if v[X] != 0 || v[Y] != 0 || v[Z] != 0 {
+		t.Errorf("at cell (0,0,0): got %v, want (0,0,0)", v)
+	}
+	v = f.interpolate(1.5, 1.5, 0.5)
+	if v[X] != 1 || v[Y] != 1 || v[Z] != 1 {
+		t.Errorf("at cell (1,1,0): got %v, want (1,1,1)", v)
+	}
+}
+
+func TestOVFInterpolateMidpoint(t *testing.T) {
+	f := parseOVF(bufio.NewReader(strings.NewReader(testOVF)))
+	// Halfway between (0,0,0)=0 and (1,0,0)=1 along x.
+	v := f.interpolate(1, 0.5, 0.5)
+	if v[X] < 0.49 || v[X] > 0.51 {
+		t.Errorf("midpoint interpolation: got mx=%v, want ~0.5", v[X])
+	}
+}
+
+func binaryOVFHeader(format string) string {
+	return "# OVF 2.0\n" +
+		"# xnodes: 1\n" +
+		"# ynodes: 1\n" +
+		"# znodes: 1\n" +
+		"# xstepsize: 1\n" +
+		"# ystepsize: 1\n" +
+		"# zstepsize: 1\n" +
+		"# valuedim: 3\n" +
+		"# Begin: Data " + format + "\n"
+}
+
+func TestParseOVFBinary4AcceptsCorrectControlNumber(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(binaryOVFHeader("Binary 4"))
+	binary.Write(&buf, binary.LittleEndian, ovfControl4)
+	binary.Write(&buf, binary.LittleEndian, []float32{1, 2, 3})
+
+	f := parseOVF(bufio.NewReader(&buf))
+	if f.at(0, 0, 0, 0) != 1 || f.at(0, 0, 0, 1) != 2 || f.at(0, 0, 0, 2) != 3 {
+		t.Fatalf("decoded binary 4 data does not match input")
+	}
+}
+
+func TestParseOVFBinary4RejectsBadControlNumber(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("parseOVF with a wrong control number: got no panic, want one")
+		}
+	}()
+	var buf bytes.Buffer
+	buf.WriteString(binaryOVFHeader("Binary 4"))
+	binary.Write(&buf, binary.LittleEndian, float32(0)) // wrong control number
+	binary.Write(&buf, binary.LittleEndian, []float32{1, 2, 3})
+	parseOVF(bufio.NewReader(&buf))
+}
+
+func TestOVFExtrapolateClampsToBoundary(t *testing.T) {
+	f := parseOVF(bufio.NewReader(strings.NewReader(testOVF)))
+	inside := f.interpolate(1.5, 1.5, 0.5)
+	// Far outside the source bounding box: extrapolation should clamp
+	// to the nearest boundary value, not diverge.
+	outside := f.interpolate(1000, 1000, 0.5)
+	if outside != inside {
+		t.Errorf("extrapolation past bbox: got %v, want clamp to boundary value %v", outside, inside)
+	}
+}
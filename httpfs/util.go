@@ -5,8 +5,10 @@ package httpfs
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 )
 
 const BUFSIZE = 16 * 1024 * 1024 // bufio buffer size
@@ -34,8 +36,26 @@ type WriteCloseFlusher interface {
 	Flush() error
 }
 
-// open a file for reading
+// Open a file for streaming, buffered reading. Unlike ReadAll, it does not
+// read the whole file into memory up front: it issues HTTP Range requests
+// against the backing server in BUFSIZE-sized chunks, refilling a
+// bufio.Reader on demand. This lets multi-GB OVF/vtk outputs be consumed
+// without holding the whole file in RAM.
 func Open(URL string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bufio.NewReaderSize(&rangeReader{URL: URL}, BUFSIZE)), nil
+}
+
+func MustOpen(URL string) io.ReadCloser {
+	f, err := Open(URL)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// ReadAll reads the entire file at URL into memory up front and returns a
+// reader over that in-memory copy. Prefer Open for large files.
+func ReadAll(URL string) (io.ReadCloser, error) {
 	data, err := Read(URL)
 	if err != nil {
 		return nil, err
@@ -43,14 +63,70 @@ func Open(URL string) (io.ReadCloser, error) {
 	return ioutil.NopCloser(bytes.NewReader(data)), nil
 }
 
-func MustOpen(URL string) io.ReadCloser {
-	f, err := Open(URL)
+// MustReadAll is like ReadAll but panics on error.
+func MustReadAll(URL string) io.ReadCloser {
+	f, err := ReadAll(URL)
 	if err != nil {
 		panic(err)
 	}
 	return f
 }
 
+// rangeReader is an io.Reader that pulls successive byte ranges of URL
+// from the httpfs server via ReadAt, advancing its offset as it's read.
+// It's meant to be wrapped in a bufio.Reader so reads come in BUFSIZE
+// chunks rather than one HTTP request per byte.
+type rangeReader struct {
+	URL string
+	off int64
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	n, err := ReadAt(r.URL, p, r.off)
+	r.off += int64(n)
+	return n, err
+}
+
+// ReadAt reads len(p) bytes of the file at URL starting at byte offset
+// off, via an HTTP Range request, and copies them into p. It returns
+// io.EOF (possibly together with a short read) once off+len(p) runs past
+// the end of the file; a read that ends exactly at the end returns nil.
+func ReadAt(URL string, p []byte, off int64) (int, error) {
+	req, err := http.NewRequest("GET", URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// A compliant server answers a range starting exactly at the file's
+	// length (which happens when the file size is a multiple of BUFSIZE
+	// and the caller asks for the next, empty, chunk) with 416 rather
+	// than 206. That's a clean EOF, not a broken server.
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return 0, io.EOF
+	}
+
+	// A plain 200 OK means the server ignored our Range header and is
+	// about to hand back the whole file starting at byte 0, not at off.
+	// Accepting that here would silently corrupt every read past the
+	// first chunk, so only a genuine 206 counts as success.
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("httpfs: ReadAt %s: server does not support range requests (got %s, need 206 Partial Content)", URL, resp.Status)
+	}
+
+	n, err := io.ReadFull(resp.Body, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
 func Touch(URL string) error {
 	return Append(URL, []byte{})
 }
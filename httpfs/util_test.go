@@ -0,0 +1,115 @@
+package httpfs
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// rangeServer serves data out of a fixed in-memory byte slice, honoring
+// Range requests the way a compliant httpfs server would: 206 with the
+// requested slice, or 416 once the requested start is past the end.
+func rangeServer(data []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, end := 0, len(data)-1
+		if rng := r.Header.Get("Range"); rng != "" {
+			var err error
+			parts := strings.SplitN(strings.TrimPrefix(rng, "bytes="), "-", 2)
+			if start, err = strconv.Atoi(parts[0]); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if end, err = strconv.Atoi(parts[1]); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if start >= len(data) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		if end >= len(data) {
+			end = len(data) - 1
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[start : end+1])
+	}))
+}
+
+func TestReadAtAgainstRangeServer(t *testing.T) {
+	want := []byte("0123456789abcdef")
+	srv := rangeServer(want)
+	defer srv.Close()
+
+	got := make([]byte, 4)
+	n, err := ReadAt(srv.URL, got, 4)
+	if err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if n != 4 || string(got) != "4567" {
+		t.Errorf("ReadAt(off=4, len=4): got %q, want %q", got[:n], "4567")
+	}
+}
+
+// Reading exactly to the end of a file whose length is a multiple of the
+// chunk size must surface a clean io.EOF, not an error, even though the
+// server answers the trailing empty chunk with 416.
+func TestReadAtExactLengthReturnsEOF(t *testing.T) {
+	want := []byte("01234567") // 8 bytes, 2 chunks of 4
+	srv := rangeServer(want)
+	defer srv.Close()
+
+	buf := make([]byte, 4)
+	if _, err := ReadAt(srv.URL, buf, 0); err != nil {
+		t.Fatalf("ReadAt(off=0): %v", err)
+	}
+	if _, err := ReadAt(srv.URL, buf, 4); err != nil {
+		t.Fatalf("ReadAt(off=4): %v", err)
+	}
+	if _, err := ReadAt(srv.URL, buf, 8); err != io.EOF {
+		t.Errorf("ReadAt(off=8) past end of an exact-multiple file: got err=%v, want io.EOF", err)
+	}
+}
+
+// Open must stream the whole file correctly when the server honors Range
+// requests.
+func TestOpenStreamsWholeFile(t *testing.T) {
+	want := []byte(strings.Repeat("mumax", 1000))
+	srv := rangeServer(want)
+	defer srv.Close()
+
+	r, err := Open(srv.URL)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll on streamed reader: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("streamed content does not match: got %d bytes, want %d bytes", len(got), len(want))
+	}
+}
+
+// A server that ignores Range headers and always answers 200 must not be
+// treated as a successful partial read.
+func TestReadAtRejectsPlain200(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("whole file from the start"))
+	}))
+	defer srv.Close()
+
+	_, err := ReadAt(srv.URL, make([]byte, 4), 10)
+	if err == nil {
+		t.Fatal("ReadAt against a server that ignores Range: got nil error, want one")
+	}
+}